@@ -0,0 +1,51 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/delgoden/wallet/pkg/api"
+	"github.com/delgoden/wallet/pkg/wallet"
+	"github.com/gorilla/mux"
+)
+
+func main() {
+	addr := os.Getenv("WALLET_ADDR")
+	if addr == "" {
+		addr = ":8080"
+	}
+
+	exportDir := os.Getenv("WALLET_EXPORT_DIR")
+	if exportDir == "" {
+		exportDir = "export"
+	}
+
+	dbPath := os.Getenv("WALLET_DB_PATH")
+	if dbPath == "" {
+		dbPath = "wallet.db"
+	}
+
+	db, err := wallet.OpenSQLite(dbPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	repo := wallet.NewSQLRepository(db)
+	if err := repo.Migrate(); err != nil {
+		log.Fatal(err)
+	}
+
+	svc, err := wallet.NewService(repo)
+	if err != nil {
+		log.Fatal(err)
+	}
+	handler := api.NewHandler(svc, exportDir)
+
+	router := mux.NewRouter()
+	handler.Register(router)
+
+	log.Printf("wallet-server listening on %s", addr)
+	if err := http.ListenAndServe(addr, router); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,323 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/delgoden/wallet/pkg/types"
+	"github.com/delgoden/wallet/pkg/wallet"
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes a wallet.Service over HTTP with JSON request/response bodies.
+type Handler struct {
+	svc       *wallet.Service
+	exportDir string
+}
+
+// NewHandler creates a Handler backed by svc. exportDir confines the
+// directories export/import requests may name: a request's dir is resolved
+// relative to exportDir, and rejected if it's absolute or escapes exportDir
+// via "..". This keeps an unauthenticated caller from pointing Export/Import
+// at an arbitrary path on the host filesystem.
+func NewHandler(svc *wallet.Service, exportDir string) *Handler {
+	return &Handler{svc: svc, exportDir: exportDir}
+}
+
+// Register wires the handler's routes onto router.
+func (h *Handler) Register(router *mux.Router) {
+	router.HandleFunc("/accounts", h.registerAccount).Methods(http.MethodPost)
+	router.HandleFunc("/accounts/authenticate", h.authenticate).Methods(http.MethodPost)
+	router.HandleFunc("/accounts/{id}", h.findAccountByID).Methods(http.MethodGet)
+	router.HandleFunc("/accounts/{id}/deposit", h.deposit).Methods(http.MethodPost)
+	router.HandleFunc("/payments", h.pay).Methods(http.MethodPost)
+	router.HandleFunc("/payments/{id}", h.findPaymentByID).Methods(http.MethodGet)
+	router.HandleFunc("/payments/{id}/reject", h.reject).Methods(http.MethodPost)
+	router.HandleFunc("/payments/{id}/repeat", h.repeat).Methods(http.MethodPost)
+	router.HandleFunc("/payments/{id}/favorite", h.favoritePayment).Methods(http.MethodPost)
+	router.HandleFunc("/favorites/{id}/pay", h.payFromFavorite).Methods(http.MethodPost)
+	router.HandleFunc("/export", h.export).Methods(http.MethodPost)
+	router.HandleFunc("/import", h.importData).Methods(http.MethodPost)
+}
+
+// errorStatus maps wallet service errors onto HTTP status codes.
+var errorStatus = map[error]int{
+	wallet.ErrAccountNotFound:      http.StatusNotFound,
+	wallet.ErrPaymentNotFound:      http.StatusNotFound,
+	wallet.ErrFavoriteNotFound:     http.StatusNotFound,
+	wallet.ErrNotEnoughBalance:     http.StatusPaymentRequired,
+	wallet.ErrPhoneRegistered:      http.StatusConflict,
+	wallet.ErrAmountMustBePositive: http.StatusBadRequest,
+	wallet.ErrWeakPassword:         http.StatusBadRequest,
+	wallet.ErrCredentialsTooLong:   http.StatusBadRequest,
+	wallet.ErrInvalidCredentials:   http.StatusUnauthorized,
+	wallet.ErrInvalidRate:          http.StatusBadRequest,
+	wallet.ErrUnknownCurrencyRate:  http.StatusBadRequest,
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// accountResponse is the public view of a types.Account: it never carries
+// the password hash over the wire.
+type accountResponse struct {
+	ID       int64          `json:"id"`
+	Phone    types.Phone    `json:"phone"`
+	Balance  types.Money    `json:"balance"`
+	Currency types.Currency `json:"currency"`
+}
+
+func toAccountResponse(account *types.Account) accountResponse {
+	return accountResponse{ID: account.ID, Phone: account.Phone, Balance: account.Balance, Currency: account.Currency}
+}
+
+func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status, ok := errorStatus[err]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+type registerAccountRequest struct {
+	Phone    types.Phone    `json:"phone"`
+	Password string         `json:"password"`
+	Currency types.Currency `json:"currency"`
+}
+
+func (h *Handler) registerAccount(w http.ResponseWriter, r *http.Request) {
+	var req registerAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	account, err := h.svc.RegisterAccount(req.Phone, req.Password, req.Currency)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAccountResponse(account))
+}
+
+func (h *Handler) findAccountByID(w http.ResponseWriter, r *http.Request) {
+	id, err := parseInt64(mux.Vars(r)["id"])
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	account, err := h.svc.FindAccountByID(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAccountResponse(account))
+}
+
+type authenticateRequest struct {
+	Phone    types.Phone `json:"phone"`
+	Password string      `json:"password"`
+}
+
+func (h *Handler) authenticate(w http.ResponseWriter, r *http.Request) {
+	var req authenticateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	account, err := h.svc.Authenticate(req.Phone, req.Password)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, toAccountResponse(account))
+}
+
+type depositRequest struct {
+	Amount   types.Money    `json:"amount"`
+	Currency types.Currency `json:"currency"`
+}
+
+func (h *Handler) deposit(w http.ResponseWriter, r *http.Request) {
+	id, err := parseInt64(mux.Vars(r)["id"])
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	var req depositRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.svc.Deposit(id, req.Amount, req.Currency); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+type payRequest struct {
+	AccountID int64                 `json:"account_id"`
+	Amount    types.Money           `json:"amount"`
+	Currency  types.Currency        `json:"currency"`
+	Category  types.PaymentCategory `json:"category"`
+}
+
+func (h *Handler) pay(w http.ResponseWriter, r *http.Request) {
+	var req payRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	payment, err := h.svc.Pay(req.AccountID, req.Amount, req.Currency, req.Category)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, payment)
+}
+
+func (h *Handler) findPaymentByID(w http.ResponseWriter, r *http.Request) {
+	payment, err := h.svc.FindPaymentByID(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, payment)
+}
+
+func (h *Handler) reject(w http.ResponseWriter, r *http.Request) {
+	if err := h.svc.Reject(mux.Vars(r)["id"]); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (h *Handler) repeat(w http.ResponseWriter, r *http.Request) {
+	payment, err := h.svc.Repeat(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, payment)
+}
+
+type favoritePaymentRequest struct {
+	Name string `json:"name"`
+}
+
+func (h *Handler) favoritePayment(w http.ResponseWriter, r *http.Request) {
+	var req favoritePaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	favorite, err := h.svc.FavoritePayment(mux.Vars(r)["id"], req.Name)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, favorite)
+}
+
+func (h *Handler) payFromFavorite(w http.ResponseWriter, r *http.Request) {
+	payment, err := h.svc.PayFromFavorite(mux.Vars(r)["id"])
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, payment)
+}
+
+type dirRequest struct {
+	Dir string `json:"dir"`
+}
+
+// ErrInvalidExportDir is returned when a dirRequest names an absolute path or
+// one that escapes the handler's configured exportDir.
+var ErrInvalidExportDir = errors.New("dir must be a relative path inside the configured export directory")
+
+// resolveExportDir joins dir onto h.exportDir, rejecting dir if it's absolute
+// or if the result would escape h.exportDir (e.g. via ".." segments).
+func (h *Handler) resolveExportDir(dir string) (string, error) {
+	if dir == "" || filepath.IsAbs(dir) {
+		return "", ErrInvalidExportDir
+	}
+
+	resolved := filepath.Join(h.exportDir, dir)
+	rel, err := filepath.Rel(h.exportDir, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", ErrInvalidExportDir
+	}
+
+	return resolved, nil
+}
+
+func (h *Handler) export(w http.ResponseWriter, r *http.Request) {
+	var req dirRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	dir, err := h.resolveExportDir(req.Dir)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.svc.Export(dir); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (h *Handler) importData(w http.ResponseWriter, r *http.Request) {
+	var req dirRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	dir, err := h.resolveExportDir(req.Dir)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.svc.Import(dir); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, struct{}{})
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/delgoden/wallet/pkg/types"
+	"github.com/delgoden/wallet/pkg/wallet"
+	"github.com/gorilla/mux"
+)
+
+func newTestServer(t *testing.T) *httptest.Server {
+	svc := &wallet.Service{}
+	handler := NewHandler(svc, t.TempDir())
+	router := mux.NewRouter()
+	handler.Register(router)
+	return httptest.NewServer(router)
+}
+
+// bootServer opens a fresh connection to the SQLite database at dbPath,
+// migrates it, hydrates a wallet.Service from it the way cmd/wallet-server's
+// main does on every boot, and serves it over HTTP.
+func bootServer(t *testing.T, dbPath string) *httptest.Server {
+	t.Helper()
+
+	db, err := wallet.OpenSQLite(dbPath)
+	if err != nil {
+		t.Fatalf("OpenSQLite(): error = %v", err)
+	}
+	repo := wallet.NewSQLRepository(db)
+	if err := repo.Migrate(); err != nil {
+		t.Fatalf("Migrate(): error = %v", err)
+	}
+
+	svc, err := wallet.NewService(repo)
+	if err != nil {
+		t.Fatalf("NewService(): error = %v", err)
+	}
+
+	handler := NewHandler(svc, t.TempDir())
+	router := mux.NewRouter()
+	handler.Register(router)
+	return httptest.NewServer(router)
+}
+
+func TestServer_restart_survivesAccountAndLedgerState(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "wallet.db")
+
+	server := bootServer(t, dbPath)
+	_, account := doJSON(t, http.MethodPost, server.URL+"/accounts", registerAccountRequest{Phone: "992000000001", Password: "Tr0ub4dor&3"})
+	accountID := fmt.Sprintf("%.0f", account["id"].(float64))
+	doJSON(t, http.MethodPost, server.URL+"/accounts/"+accountID+"/deposit", depositRequest{Amount: 1000_00})
+	server.Close()
+
+	restarted := bootServer(t, dbPath)
+	defer restarted.Close()
+
+	resp, got := doJSON(t, http.MethodGet, restarted.URL+"/accounts/"+accountID, nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("FindAccountByID() after restart: status = %v, want %v", resp.StatusCode, http.StatusOK)
+	}
+	if got["balance"] != float64(1000_00) {
+		t.Errorf("FindAccountByID() after restart: balance = %v, want %v", got["balance"], 1000_00)
+	}
+}
+
+func doJSON(t *testing.T, method, url string, body interface{}) (*http.Response, map[string]interface{}) {
+	t.Helper()
+
+	var reader *bytes.Buffer
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("can't marshal request body, error = %v", err)
+		}
+		reader = bytes.NewBuffer(data)
+	} else {
+		reader = bytes.NewBuffer(nil)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatalf("can't create request, error = %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("can't perform request, error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	var payload map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("can't decode response body, error = %v", err)
+	}
+
+	return resp, payload
+}
+
+func TestHandler_RegisterAccount_success(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	resp, payload := doJSON(t, http.MethodPost, server.URL+"/accounts", registerAccountRequest{Phone: "992000000001", Password: "Tr0ub4dor&3"})
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("RegisterAccount(): status = %v, want %v", resp.StatusCode, http.StatusOK)
+	}
+	if payload["phone"] != "992000000001" {
+		t.Errorf("RegisterAccount(): phone = %v, want %v", payload["phone"], "992000000001")
+	}
+}
+
+func TestHandler_RegisterAccount_phoneRegistered(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	doJSON(t, http.MethodPost, server.URL+"/accounts", registerAccountRequest{Phone: "992000000001", Password: "Tr0ub4dor&3"})
+	resp, payload := doJSON(t, http.MethodPost, server.URL+"/accounts", registerAccountRequest{Phone: "992000000001", Password: "Tr0ub4dor&3"})
+
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("RegisterAccount(): status = %v, want %v", resp.StatusCode, http.StatusConflict)
+	}
+	if payload["error"] != wallet.ErrPhoneRegistered.Error() {
+		t.Errorf("RegisterAccount(): error = %v, want %v", payload["error"], wallet.ErrPhoneRegistered)
+	}
+}
+
+func TestHandler_Authenticate_wrongPassword(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	doJSON(t, http.MethodPost, server.URL+"/accounts", registerAccountRequest{Phone: "992000000001", Password: "Tr0ub4dor&3"})
+
+	resp, payload := doJSON(t, http.MethodPost, server.URL+"/accounts/authenticate", authenticateRequest{Phone: "992000000001", Password: "wrong-password"})
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Authenticate(): status = %v, want %v", resp.StatusCode, http.StatusUnauthorized)
+	}
+	if payload["error"] != wallet.ErrInvalidCredentials.Error() {
+		t.Errorf("Authenticate(): error = %v, want %v", payload["error"], wallet.ErrInvalidCredentials)
+	}
+}
+
+func TestHandler_Deposit_accountNotFound(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	resp, payload := doJSON(t, http.MethodPost, server.URL+"/accounts/1/deposit", depositRequest{Amount: 100})
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Deposit(): status = %v, want %v", resp.StatusCode, http.StatusNotFound)
+	}
+	if payload["error"] != wallet.ErrAccountNotFound.Error() {
+		t.Errorf("Deposit(): error = %v, want %v", payload["error"], wallet.ErrAccountNotFound)
+	}
+}
+
+func TestHandler_Pay_notEnoughBalance(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	_, account := doJSON(t, http.MethodPost, server.URL+"/accounts", registerAccountRequest{Phone: "992000000001", Password: "Tr0ub4dor&3"})
+	accountID := int64(account["id"].(float64))
+
+	resp, payload := doJSON(t, http.MethodPost, server.URL+"/payments", payRequest{
+		AccountID: accountID,
+		Amount:    100,
+		Category:  types.PaymentCategory("auto"),
+	})
+
+	if resp.StatusCode != http.StatusPaymentRequired {
+		t.Errorf("Pay(): status = %v, want %v", resp.StatusCode, http.StatusPaymentRequired)
+	}
+	if payload["error"] != wallet.ErrNotEnoughBalance.Error() {
+		t.Errorf("Pay(): error = %v, want %v", payload["error"], wallet.ErrNotEnoughBalance)
+	}
+}
+
+func TestHandler_Pay_success(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	_, account := doJSON(t, http.MethodPost, server.URL+"/accounts", registerAccountRequest{Phone: "992000000001", Password: "Tr0ub4dor&3"})
+	accountID := int64(account["id"].(float64))
+
+	doJSON(t, http.MethodPost, server.URL+"/accounts/1/deposit", depositRequest{Amount: 1000})
+
+	resp, payload := doJSON(t, http.MethodPost, server.URL+"/payments", payRequest{
+		AccountID: accountID,
+		Amount:    100,
+		Category:  types.PaymentCategory("auto"),
+	})
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Pay(): status = %v, want %v", resp.StatusCode, http.StatusOK)
+	}
+	if payload["ID"] == "" {
+		t.Errorf("Pay(): empty payment id returned")
+	}
+}
+
+func TestHandler_Export_rejectsEscapingDir(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	for _, dir := range []string{"../../../etc/cron.d", "/etc/cron.d", ".."} {
+		resp, payload := doJSON(t, http.MethodPost, server.URL+"/export", dirRequest{Dir: dir})
+
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Export(%q): status = %v, want %v", dir, resp.StatusCode, http.StatusBadRequest)
+		}
+		if payload["error"] != ErrInvalidExportDir.Error() {
+			t.Errorf("Export(%q): error = %v, want %v", dir, payload["error"], ErrInvalidExportDir)
+		}
+	}
+}
+
+func TestHandler_Export_allowsSubdirectory(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+
+	resp, _ := doJSON(t, http.MethodPost, server.URL+"/export", dirRequest{Dir: "backup"})
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Export(): status = %v, want %v", resp.StatusCode, http.StatusOK)
+	}
+}
@@ -0,0 +1,7 @@
+package api
+
+import "strconv"
+
+func parseInt64(s string) (int64, error) {
+	return strconv.ParseInt(s, 10, 64)
+}
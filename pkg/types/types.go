@@ -1,8 +1,21 @@
 package types
 
+import "time"
+
 // Money amount of money in minimum currency units (cents, rubles, dirhams, etc.)
 type Money int64
 
+// Currency is an ISO 4217 currency code (e.g. "USD", "RUB", "TJS").
+type Currency string
+
+// Common currencies. No conversion rates between them are preset; they are
+// added with wallet.Service.SetRate.
+const (
+	USD Currency = "USD"
+	RUB Currency = "RUB"
+	TJS Currency = "TJS"
+)
+
 // PaymentCategory the category in which the payment was made (cars, pharmacies, food, etc.)
 type PaymentCategory string
 
@@ -21,6 +34,7 @@ type Payment struct {
 	ID        string
 	AccountID int64
 	Amount    Money
+	Currency  Currency
 	Category  PaymentCategory
 	Status    PaymentStatus
 }
@@ -30,9 +44,11 @@ type Phone string
 
 // Account present info for user account
 type Account struct {
-	ID      int64
-	Phone   Phone
-	Balance Money
+	ID           int64
+	Phone        Phone
+	Balance      Money
+	Currency     Currency
+	PasswordHash string `json:"password_hash,omitempty"`
 }
 
 // Favorite present favorite payments user
@@ -41,6 +57,7 @@ type Favorite struct {
 	AccountID int64
 	Name      string
 	Amount    Money
+	Currency  Currency
 	Category  PaymentCategory
 }
 
@@ -48,4 +65,23 @@ type Favorite struct {
 type Progress struct {
 	Part   int
 	Result Money
+}
+
+// Transaction is one append-only entry in an account's ledger: a transfer of
+// Amount from Debit to Credit. An account ID of 0 stands for a party outside
+// the ledger (an external deposit source, or a merchant paid out to), so a
+// Deposit credits an account with Debit 0, and a Pay debits an account with
+// Credit 0. Reference is the ID of the payment that caused the entry (a
+// payment, a payment from a favorite, or a reject refund); it is empty for a
+// deposit. Note labels what kind of transaction this is
+// (e.g. "deposit", "payment", "reject refund").
+type Transaction struct {
+	ID        string
+	Debit     int64
+	Credit    int64
+	Amount    Money
+	Currency  Currency
+	Timestamp time.Time
+	Reference string
+	Note      string
 }
\ No newline at end of file
@@ -0,0 +1,191 @@
+package wallet
+
+import (
+	"context"
+	"sync"
+
+	"github.com/delgoden/wallet/pkg/types"
+)
+
+// splitPayments partitions payments into at most parts roughly-equal,
+// contiguous chunks. It never returns more chunks than payments, and returns
+// a single chunk if parts is less than 1.
+func splitPayments(payments []*types.Payment, parts int) [][]*types.Payment {
+	if parts < 1 {
+		parts = 1
+	}
+	if parts > len(payments) {
+		parts = len(payments)
+	}
+	if parts == 0 {
+		return nil
+	}
+
+	chunks := make([][]*types.Payment, 0, parts)
+	chunkSize := len(payments) / parts
+	remainder := len(payments) % parts
+
+	start := 0
+	for i := 0; i < parts; i++ {
+		size := chunkSize
+		if i < remainder {
+			size++
+		}
+		chunks = append(chunks, payments[start:start+size])
+		start += size
+	}
+	return chunks
+}
+
+// SumPaymentsProgress sums every payment's amount, converted into
+// PreferredCurrency, splitting the work across goroutines workers. A payment
+// whose currency has no known rate into PreferredCurrency is left out of the
+// sum. Each worker reports its partial sum as a types.Progress on the
+// returned channel, which is closed once every worker has reported or ctx is
+// done.
+func (s *Service) SumPaymentsProgress(ctx context.Context, goroutines int) <-chan types.Progress {
+	s.mu.RLock()
+	preferred := s.preferredCurrencyLocked()
+	rates := s.ratesSnapshot()
+	payments := make([]*types.Payment, len(s.payments))
+	copy(payments, s.payments)
+	s.mu.RUnlock()
+
+	chunks := splitPayments(payments, goroutines)
+	ch := make(chan types.Progress)
+
+	var wg sync.WaitGroup
+	wg.Add(len(chunks))
+	for part, chunk := range chunks {
+		go func(part int, chunk []*types.Payment) {
+			defer wg.Done()
+
+			var total types.Money
+			for _, payment := range chunk {
+				converted, err := convertWithRates(rates, payment.Amount, payment.Currency, preferred)
+				if err != nil {
+					continue
+				}
+				total += converted
+			}
+
+			select {
+			case ch <- types.Progress{Part: part, Result: total}:
+			case <-ctx.Done():
+			}
+		}(part, chunk)
+	}
+
+	go func() {
+		wg.Wait()
+		close(ch)
+	}()
+
+	return ch
+}
+
+// SumPaymentsConcurrent sums every payment's amount, converted into
+// PreferredCurrency, using goroutines workers via SumPaymentsProgress. Unlike
+// SumPayments, it does not return ErrUnknownCurrencyRate: a payment that can't
+// be converted is left out of the total. It returns ctx.Err() if ctx is
+// canceled before every worker has reported.
+func (s *Service) SumPaymentsConcurrent(ctx context.Context, goroutines int) (types.Money, error) {
+	var total types.Money
+	for progress := range s.SumPaymentsProgress(ctx, goroutines) {
+		total += progress.Result
+	}
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// SumPaymentsByCategoryConcurrent totals payments per category, converted
+// into PreferredCurrency, splitting the work across goroutines workers and
+// merging their partial totals. Unlike SumPaymentsByCategory, it does not
+// return ErrUnknownCurrencyRate: a payment that can't be converted is left
+// out of its category's total. It returns ctx.Err() if ctx is canceled
+// before every worker has finished.
+func (s *Service) SumPaymentsByCategoryConcurrent(ctx context.Context, goroutines int) (map[types.PaymentCategory]types.Money, error) {
+	s.mu.RLock()
+	preferred := s.preferredCurrencyLocked()
+	rates := s.ratesSnapshot()
+	payments := make([]*types.Payment, len(s.payments))
+	copy(payments, s.payments)
+	s.mu.RUnlock()
+
+	chunks := splitPayments(payments, goroutines)
+	partials := make([]map[types.PaymentCategory]types.Money, len(chunks))
+
+	var wg sync.WaitGroup
+	wg.Add(len(chunks))
+	for part, chunk := range chunks {
+		go func(part int, chunk []*types.Payment) {
+			defer wg.Done()
+
+			totals := make(map[types.PaymentCategory]types.Money)
+			for _, payment := range chunk {
+				converted, err := convertWithRates(rates, payment.Amount, payment.Currency, preferred)
+				if err != nil {
+					continue
+				}
+				totals[payment.Category] += converted
+			}
+			partials[part] = totals
+		}(part, chunk)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	totals := make(map[types.PaymentCategory]types.Money)
+	for _, partial := range partials {
+		for category, amount := range partial {
+			totals[category] += amount
+		}
+	}
+	return totals, nil
+}
+
+// FilterPayments returns every payment belonging to accountID, searching
+// across goroutines workers in parallel. It returns ErrAccountNotFound if no
+// account with accountID is registered.
+func (s *Service) FilterPayments(accountID int64, goroutines int) ([]types.Payment, error) {
+	if _, err := s.FindAccountByID(accountID); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	payments := make([]*types.Payment, len(s.payments))
+	copy(payments, s.payments)
+	s.mu.RUnlock()
+
+	chunks := splitPayments(payments, goroutines)
+	partials := make([][]types.Payment, len(chunks))
+
+	var wg sync.WaitGroup
+	wg.Add(len(chunks))
+	for part, chunk := range chunks {
+		go func(part int, chunk []*types.Payment) {
+			defer wg.Done()
+
+			var matched []types.Payment
+			for _, payment := range chunk {
+				if payment.AccountID == accountID {
+					matched = append(matched, *payment)
+				}
+			}
+			partials[part] = matched
+		}(part, chunk)
+	}
+	wg.Wait()
+
+	var result []types.Payment
+	for _, partial := range partials {
+		result = append(result, partial...)
+	}
+	return result, nil
+}
@@ -0,0 +1,136 @@
+package wallet
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/delgoden/wallet/pkg/types"
+)
+
+func newSumTestService(t testing.TB, payments int) *testService {
+	t.Helper()
+
+	s := newTestService()
+	account, err := s.RegisterAccount("992000000001", "Tr0ub4dor&3", types.TJS)
+	if err != nil {
+		t.Fatalf("RegisterAccount(): error = %v", err)
+	}
+	if err := s.Deposit(account.ID, types.Money(payments)*100_00, types.TJS); err != nil {
+		t.Fatalf("Deposit(): error = %v", err)
+	}
+
+	for i := 0; i < payments; i++ {
+		if _, err := s.Pay(account.ID, 100_00, types.TJS, "auto"); err != nil {
+			t.Fatalf("Pay(): error = %v", err)
+		}
+	}
+
+	return s
+}
+
+func TestService_SumPaymentsConcurrent_matchesSumPayments(t *testing.T) {
+	s := newSumTestService(t, 137)
+
+	want, err := s.SumPayments()
+	if err != nil {
+		t.Fatalf("SumPayments(): error = %v", err)
+	}
+
+	got, err := s.SumPaymentsConcurrent(context.Background(), 8)
+	if err != nil {
+		t.Fatalf("SumPaymentsConcurrent(): error = %v", err)
+	}
+
+	if got != want {
+		t.Errorf("SumPaymentsConcurrent(): got = %v, want %v", got, want)
+	}
+}
+
+func TestService_SumPaymentsConcurrent_canceledContext(t *testing.T) {
+	s := newSumTestService(t, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := s.SumPaymentsConcurrent(ctx, 4)
+	if err != context.Canceled {
+		t.Errorf("SumPaymentsConcurrent(): error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestService_SumPaymentsByCategoryConcurrent_matchesSumPaymentsByCategory(t *testing.T) {
+	s := newSumTestService(t, 97)
+
+	want, err := s.SumPaymentsByCategory()
+	if err != nil {
+		t.Fatalf("SumPaymentsByCategory(): error = %v", err)
+	}
+
+	got, err := s.SumPaymentsByCategoryConcurrent(context.Background(), 8)
+	if err != nil {
+		t.Fatalf("SumPaymentsByCategoryConcurrent(): error = %v", err)
+	}
+
+	if len(got) != len(want) || got["auto"] != want["auto"] {
+		t.Errorf("SumPaymentsByCategoryConcurrent(): got = %v, want %v", got, want)
+	}
+}
+
+func TestService_FilterPayments_accountNotFound(t *testing.T) {
+	s := newTestService()
+	_, err := s.FilterPayments(10, 4)
+	if err != ErrAccountNotFound {
+		t.Errorf("FilterPayments(): error = %v, want %v", err, ErrAccountNotFound)
+	}
+}
+
+func TestService_FilterPayments_returnsOnlyOwnPayments(t *testing.T) {
+	s := newSumTestService(t, 25)
+
+	other, err := s.RegisterAccount("992000000002", "Tr0ub4dor&3", types.TJS)
+	if err != nil {
+		t.Fatalf("RegisterAccount(): error = %v", err)
+	}
+	if err := s.Deposit(other.ID, 100_00, types.TJS); err != nil {
+		t.Fatalf("Deposit(): error = %v", err)
+	}
+	if _, err := s.Pay(other.ID, 10_00, types.TJS, "food"); err != nil {
+		t.Fatalf("Pay(): error = %v", err)
+	}
+
+	got, err := s.FilterPayments(other.ID, 4)
+	if err != nil {
+		t.Fatalf("FilterPayments(): error = %v", err)
+	}
+	if len(got) != 1 || got[0].AccountID != other.ID {
+		t.Errorf("FilterPayments(): got = %v", got)
+	}
+}
+
+func BenchmarkService_SumPayments(b *testing.B) {
+	s := newSumTestService(b, 10_000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.SumPayments(); err != nil {
+			b.Fatalf("SumPayments(): error = %v", err)
+		}
+	}
+}
+
+func BenchmarkService_SumPaymentsConcurrent(b *testing.B) {
+	s := newSumTestService(b, 10_000)
+	ctx := context.Background()
+
+	for _, goroutines := range []int{2, 4, 8, 16} {
+		b.Run(fmt.Sprintf("goroutines=%d", goroutines), func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := s.SumPaymentsConcurrent(ctx, goroutines); err != nil {
+					b.Fatalf("SumPaymentsConcurrent(): error = %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package wallet
+
+import (
+	"math"
+
+	"github.com/delgoden/wallet/pkg/types"
+)
+
+// defaultCurrency is used by PreferredCurrency and account registration
+// when no currency has been set explicitly.
+const defaultCurrency = types.TJS
+
+// preferredCurrencyLocked is PreferredCurrency without locking, for callers
+// that already hold s.mu.
+func (s *Service) preferredCurrencyLocked() types.Currency {
+	if s.preferredCurrency == "" {
+		return defaultCurrency
+	}
+	return s.preferredCurrency
+}
+
+// PreferredCurrency returns the currency aggregate reports (SumPayments,
+// SumPaymentsByCategory) convert into, defaulting to defaultCurrency.
+func (s *Service) PreferredCurrency() types.Currency {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.preferredCurrencyLocked()
+}
+
+// SetPreferredCurrency changes the currency used by aggregate reports.
+func (s *Service) SetPreferredCurrency(currency types.Currency) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.preferredCurrency = currency
+}
+
+// SetRate records the exchange rate for converting one unit of from into to
+// units of to (e.g. SetRate(USD, RUB, 90) says 1 USD = 90 RUB). The reverse
+// conversion is derived automatically.
+func (s *Service) SetRate(from, to types.Currency, rate float64) error {
+	if rate <= 0 {
+		return ErrInvalidRate
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rates == nil {
+		s.rates = make(map[types.Currency]map[types.Currency]float64)
+	}
+	if s.rates[from] == nil {
+		s.rates[from] = make(map[types.Currency]float64)
+	}
+	s.rates[from][to] = rate
+
+	return nil
+}
+
+// ratesSnapshot returns a deep copy of the exchange rate table, for callers
+// (e.g. the concurrent workers in concurrent.go) that need to convert
+// amounts without holding s.mu for the duration of the work.
+func (s *Service) ratesSnapshot() map[types.Currency]map[types.Currency]float64 {
+	snapshot := make(map[types.Currency]map[types.Currency]float64, len(s.rates))
+	for from, rates := range s.rates {
+		inner := make(map[types.Currency]float64, len(rates))
+		for to, rate := range rates {
+			inner[to] = rate
+		}
+		snapshot[from] = inner
+	}
+	return snapshot
+}
+
+// convert converts amount from one currency to another using the rate table,
+// falling back to the inverse of a known reverse rate. It returns amount
+// unchanged if from and to are equal or either is unset. Callers must hold
+// s.mu.
+func (s *Service) convert(amount types.Money, from, to types.Currency) (types.Money, error) {
+	return convertWithRates(s.rates, amount, from, to)
+}
+
+// convertWithRates is convert's logic against an explicit rate table, so
+// callers that already hold a snapshot (e.g. concurrent.go) don't need s.mu.
+func convertWithRates(rates map[types.Currency]map[types.Currency]float64, amount types.Money, from, to types.Currency) (types.Money, error) {
+	if from == to || from == "" || to == "" {
+		return amount, nil
+	}
+
+	if rate, ok := rates[from][to]; ok {
+		return types.Money(math.Round(float64(amount) * rate)), nil
+	}
+	if rate, ok := rates[to][from]; ok {
+		return types.Money(math.Round(float64(amount) / rate)), nil
+	}
+
+	return 0, ErrUnknownCurrencyRate
+}
+
+// SumPayments totals every payment, converted into PreferredCurrency.
+func (s *Service) SumPayments() (types.Money, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	preferred := s.preferredCurrencyLocked()
+
+	var total types.Money
+	for _, payment := range s.payments {
+		converted, err := s.convert(payment.Amount, payment.Currency, preferred)
+		if err != nil {
+			return 0, err
+		}
+		total += converted
+	}
+
+	return total, nil
+}
+
+// SumPaymentsByCategory totals payments per category, converted into
+// PreferredCurrency.
+func (s *Service) SumPaymentsByCategory() (map[types.PaymentCategory]types.Money, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	preferred := s.preferredCurrencyLocked()
+
+	totals := make(map[types.PaymentCategory]types.Money)
+	for _, payment := range s.payments {
+		converted, err := s.convert(payment.Amount, payment.Currency, preferred)
+		if err != nil {
+			return nil, err
+		}
+		totals[payment.Category] += converted
+	}
+
+	return totals, nil
+}
@@ -0,0 +1,126 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/delgoden/wallet/pkg/types"
+)
+
+func TestService_SetRate_invalid(t *testing.T) {
+	s := newTestService()
+	err := s.SetRate(types.USD, types.RUB, 0)
+	if err != ErrInvalidRate {
+		t.Errorf("SetRate(): error = %v, want %v", err, ErrInvalidRate)
+	}
+}
+
+func TestService_convert_sameCurrency(t *testing.T) {
+	s := newTestService()
+	got, err := s.convert(100_00, types.USD, types.USD)
+	if err != nil {
+		t.Errorf("convert(): error = %v", err)
+		return
+	}
+	if got != 100_00 {
+		t.Errorf("convert(): got = %v, want %v", got, 100_00)
+	}
+}
+
+func TestService_convert_unknownRate(t *testing.T) {
+	s := newTestService()
+	_, err := s.convert(100_00, types.USD, types.RUB)
+	if err != ErrUnknownCurrencyRate {
+		t.Errorf("convert(): error = %v, want %v", err, ErrUnknownCurrencyRate)
+	}
+}
+
+func TestService_convert_directAndInverseRate(t *testing.T) {
+	s := newTestService()
+	if err := s.SetRate(types.USD, types.RUB, 90); err != nil {
+		t.Fatalf("SetRate(): error = %v", err)
+	}
+
+	got, err := s.convert(10_00, types.USD, types.RUB)
+	if err != nil {
+		t.Errorf("convert(): error = %v", err)
+		return
+	}
+	if got != 900_00 {
+		t.Errorf("convert(): got = %v, want %v", got, 900_00)
+	}
+
+	got, err = s.convert(900_00, types.RUB, types.USD)
+	if err != nil {
+		t.Errorf("convert(): error = %v", err)
+		return
+	}
+	if got != 10_00 {
+		t.Errorf("convert(): got = %v, want %v", got, 10_00)
+	}
+}
+
+func TestService_Deposit_convertsToAccountCurrency(t *testing.T) {
+	s := newTestService()
+	account, err := s.RegisterAccount("992000000001", "Tr0ub4dor&3", types.USD)
+	if err != nil {
+		t.Fatalf("RegisterAccount(): error = %v", err)
+	}
+	if err := s.SetRate(types.RUB, types.USD, 0.01); err != nil {
+		t.Fatalf("SetRate(): error = %v", err)
+	}
+
+	if err := s.Deposit(account.ID, 1000_00, types.RUB); err != nil {
+		t.Fatalf("Deposit(): error = %v", err)
+	}
+
+	got, err := s.FindAccountByID(account.ID)
+	if err != nil {
+		t.Fatalf("FindAccountByID(): error = %v", err)
+	}
+	if got.Balance != 10_00 {
+		t.Errorf("Deposit(): balance = %v, want %v", got.Balance, 10_00)
+	}
+}
+
+func TestService_Pay_unknownRate(t *testing.T) {
+	s := newTestService()
+	account, err := s.RegisterAccount("992000000001", "Tr0ub4dor&3", types.USD)
+	if err != nil {
+		t.Fatalf("RegisterAccount(): error = %v", err)
+	}
+	if err := s.Deposit(account.ID, 100_00, types.USD); err != nil {
+		t.Fatalf("Deposit(): error = %v", err)
+	}
+
+	_, err = s.Pay(account.ID, 10_00, types.RUB, "auto")
+	if err != ErrUnknownCurrencyRate {
+		t.Errorf("Pay(): error = %v, want %v", err, ErrUnknownCurrencyRate)
+	}
+}
+
+func TestService_SumPayments_convertsToPreferredCurrency(t *testing.T) {
+	s := newTestService()
+	s.SetPreferredCurrency(types.USD)
+	if err := s.SetRate(types.RUB, types.USD, 0.01); err != nil {
+		t.Fatalf("SetRate(): error = %v", err)
+	}
+
+	account, err := s.RegisterAccount("992000000001", "Tr0ub4dor&3", types.RUB)
+	if err != nil {
+		t.Fatalf("RegisterAccount(): error = %v", err)
+	}
+	if err := s.Deposit(account.ID, 10000_00, types.RUB); err != nil {
+		t.Fatalf("Deposit(): error = %v", err)
+	}
+	if _, err := s.Pay(account.ID, 1000_00, types.RUB, "auto"); err != nil {
+		t.Fatalf("Pay(): error = %v", err)
+	}
+
+	total, err := s.SumPayments()
+	if err != nil {
+		t.Fatalf("SumPayments(): error = %v", err)
+	}
+	if total != 10_00 {
+		t.Errorf("SumPayments(): got = %v, want %v", total, 10_00)
+	}
+}
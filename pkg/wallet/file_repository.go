@@ -0,0 +1,154 @@
+package wallet
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/delgoden/wallet/pkg/types"
+)
+
+// FileRepository is a Repository that stores accounts, payments and
+// favorites as JSON documents under a directory, one file per collection.
+// Unlike the old `;`/`|`-delimited dump format, JSON encoding means a phone
+// number, category or note containing those characters can no longer
+// corrupt the file on import.
+type FileRepository struct {
+	dir string
+}
+
+// NewFileRepository creates a FileRepository rooted at dir, creating the
+// directory if it does not already exist.
+func NewFileRepository(dir string) (*FileRepository, error) {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return nil, err
+	}
+	return &FileRepository{dir: dir}, nil
+}
+
+func (r *FileRepository) accountsPath() string     { return filepath.Join(r.dir, "accounts.json") }
+func (r *FileRepository) paymentsPath() string     { return filepath.Join(r.dir, "payments.json") }
+func (r *FileRepository) favoritesPath() string    { return filepath.Join(r.dir, "favorites.json") }
+func (r *FileRepository) transactionsPath() string { return filepath.Join(r.dir, "transactions.json") }
+
+// SaveAccount inserts account, or overwrites the existing entry with the same ID.
+func (r *FileRepository) SaveAccount(account *types.Account) error {
+	accounts, err := r.LoadAccounts()
+	if err != nil {
+		return err
+	}
+
+	for i, acc := range accounts {
+		if acc.ID == account.ID {
+			accounts[i] = account
+			return writeJSONFile(r.accountsPath(), accounts)
+		}
+	}
+	accounts = append(accounts, account)
+	return writeJSONFile(r.accountsPath(), accounts)
+}
+
+// LoadAccounts returns all saved accounts.
+func (r *FileRepository) LoadAccounts() ([]*types.Account, error) {
+	var accounts []*types.Account
+	if err := readJSONFile(r.accountsPath(), &accounts); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// SavePayment inserts payment, or overwrites the existing entry with the same ID.
+func (r *FileRepository) SavePayment(payment *types.Payment) error {
+	payments, err := r.LoadPayments()
+	if err != nil {
+		return err
+	}
+
+	for i, pay := range payments {
+		if pay.ID == payment.ID {
+			payments[i] = payment
+			return writeJSONFile(r.paymentsPath(), payments)
+		}
+	}
+	payments = append(payments, payment)
+	return writeJSONFile(r.paymentsPath(), payments)
+}
+
+// LoadPayments returns all saved payments.
+func (r *FileRepository) LoadPayments() ([]*types.Payment, error) {
+	var payments []*types.Payment
+	if err := readJSONFile(r.paymentsPath(), &payments); err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// SaveFavorite inserts favorite, or overwrites the existing entry with the same ID.
+func (r *FileRepository) SaveFavorite(favorite *types.Favorite) error {
+	favorites, err := r.LoadFavorites()
+	if err != nil {
+		return err
+	}
+
+	for i, fvr := range favorites {
+		if fvr.ID == favorite.ID {
+			favorites[i] = favorite
+			return writeJSONFile(r.favoritesPath(), favorites)
+		}
+	}
+	favorites = append(favorites, favorite)
+	return writeJSONFile(r.favoritesPath(), favorites)
+}
+
+// LoadFavorites returns all saved favorites.
+func (r *FileRepository) LoadFavorites() ([]*types.Favorite, error) {
+	var favorites []*types.Favorite
+	if err := readJSONFile(r.favoritesPath(), &favorites); err != nil {
+		return nil, err
+	}
+	return favorites, nil
+}
+
+// SaveTransaction appends transaction to the ledger file. Transactions are
+// immutable once recorded, so unlike the other Save methods this never
+// overwrites an existing entry.
+func (r *FileRepository) SaveTransaction(transaction *types.Transaction) error {
+	transactions, err := r.LoadTransactions()
+	if err != nil {
+		return err
+	}
+
+	transactions = append(transactions, transaction)
+	return writeJSONFile(r.transactionsPath(), transactions)
+}
+
+// LoadTransactions returns every recorded transaction.
+func (r *FileRepository) LoadTransactions() ([]*types.Transaction, error) {
+	var transactions []*types.Transaction
+	if err := readJSONFile(r.transactionsPath(), &transactions); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
+
+// readJSONFile decodes path into v, leaving v untouched if path doesn't exist yet.
+func readJSONFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeJSONFile encodes v into path, overwriting any previous contents.
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
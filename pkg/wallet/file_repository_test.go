@@ -0,0 +1,73 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/delgoden/wallet/pkg/types"
+)
+
+func TestFileRepository_Accounts_roundtrip(t *testing.T) {
+	repo, err := NewFileRepository(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileRepository(): error = %v", err)
+	}
+
+	account := &types.Account{ID: 1, Phone: "992000000001", Balance: 1000}
+	if err := repo.SaveAccount(account); err != nil {
+		t.Fatalf("SaveAccount(): error = %v", err)
+	}
+
+	accounts, err := repo.LoadAccounts()
+	if err != nil {
+		t.Fatalf("LoadAccounts(): error = %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].Phone != account.Phone || accounts[0].Balance != account.Balance {
+		t.Errorf("LoadAccounts(): got %+v, want [%+v]", accounts, account)
+	}
+}
+
+func TestFileRepository_Accounts_separatorSafePhone(t *testing.T) {
+	repo, err := NewFileRepository(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileRepository(): error = %v", err)
+	}
+
+	phone := types.Phone("992;000|000001")
+	account := &types.Account{ID: 1, Phone: phone, Balance: 1000}
+	if err := repo.SaveAccount(account); err != nil {
+		t.Fatalf("SaveAccount(): error = %v", err)
+	}
+
+	accounts, err := repo.LoadAccounts()
+	if err != nil {
+		t.Fatalf("LoadAccounts(): error = %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].Phone != phone {
+		t.Errorf("LoadAccounts(): phone corrupted, got %+v, want phone = %v", accounts, phone)
+	}
+}
+
+func TestFileRepository_SaveAccount_overwritesExisting(t *testing.T) {
+	repo, err := NewFileRepository(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileRepository(): error = %v", err)
+	}
+
+	account := &types.Account{ID: 1, Phone: "992000000001", Balance: 1000}
+	if err := repo.SaveAccount(account); err != nil {
+		t.Fatalf("SaveAccount(): error = %v", err)
+	}
+
+	account.Balance = 2000
+	if err := repo.SaveAccount(account); err != nil {
+		t.Fatalf("SaveAccount(): error = %v", err)
+	}
+
+	accounts, err := repo.LoadAccounts()
+	if err != nil {
+		t.Fatalf("LoadAccounts(): error = %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].Balance != 2000 {
+		t.Errorf("LoadAccounts(): got %+v, want balance = 2000", accounts)
+	}
+}
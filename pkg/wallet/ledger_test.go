@@ -0,0 +1,121 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/delgoden/wallet/pkg/types"
+)
+
+func TestService_Reject_doesNotDoubleRefund(t *testing.T) {
+	s := newTestService()
+	account, payments, err := s.addAccount(defaultTestAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payment := payments[0]
+
+	balanceBeforeReject := s.balance(account.ID)
+
+	if err := s.Reject(payment.ID); err != nil {
+		t.Fatalf("Reject(): error = %v", err)
+	}
+	if err := s.Reject(payment.ID); err != nil {
+		t.Fatalf("Reject(): second call error = %v", err)
+	}
+
+	want := balanceBeforeReject + payment.Amount
+	got := s.balance(account.ID)
+	if got != want {
+		t.Errorf("Reject(): balance after two rejects = %v, want %v", got, want)
+	}
+}
+
+func TestService_Ledger_includesDepositAndPayment(t *testing.T) {
+	s := newTestService()
+	account, payments, err := s.addAccount(defaultTestAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ledger := s.Ledger(account.ID)
+	if len(ledger) != 2 {
+		t.Fatalf("Ledger(): got %v entries, want 2", len(ledger))
+	}
+	if ledger[0].Credit != account.ID || ledger[0].Amount != defaultTestAccount.balance {
+		t.Errorf("Ledger(): deposit entry = %+v", ledger[0])
+	}
+	if ledger[1].Debit != account.ID || ledger[1].Amount != payments[0].Amount {
+		t.Errorf("Ledger(): payment entry = %+v", ledger[1])
+	}
+}
+
+func TestService_PayFromFavorite_updatesBalanceAndLedger(t *testing.T) {
+	s := newTestService()
+	account, payments, err := s.addAccount(defaultTestAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	favorite, err := s.FavoritePayment(payments[0].ID, "taxi")
+	if err != nil {
+		t.Fatalf("FavoritePayment(): error = %v", err)
+	}
+
+	balanceBefore := s.balance(account.ID)
+
+	payment, err := s.PayFromFavorite(favorite.ID)
+	if err != nil {
+		t.Fatalf("PayFromFavorite(): error = %v", err)
+	}
+
+	want := balanceBefore - payment.Amount
+	got := s.balance(account.ID)
+	if got != want {
+		t.Errorf("PayFromFavorite(): balance = %v, want %v", got, want)
+	}
+	if account.Balance != got {
+		t.Errorf("PayFromFavorite(): account.Balance = %v, want %v", account.Balance, got)
+	}
+
+	ledger := s.Ledger(account.ID)
+	last := ledger[len(ledger)-1]
+	if last.Debit != account.ID || last.Reference != payment.ID {
+		t.Errorf("PayFromFavorite(): ledger tail = %+v, want debit entry referencing %v", last, payment.ID)
+	}
+}
+
+func TestService_PayFromFavorite_notEnoughBalance(t *testing.T) {
+	s := newTestService()
+	account, err := s.RegisterAccount("992000000001", "Tr0ub4dor&3", types.TJS)
+	if err != nil {
+		t.Fatalf("RegisterAccount(): error = %v", err)
+	}
+	if err := s.Deposit(account.ID, 100_00, types.TJS); err != nil {
+		t.Fatalf("Deposit(): error = %v", err)
+	}
+	payment, err := s.Pay(account.ID, 100_00, types.TJS, "auto")
+	if err != nil {
+		t.Fatalf("Pay(): error = %v", err)
+	}
+	favorite, err := s.FavoritePayment(payment.ID, "taxi")
+	if err != nil {
+		t.Fatalf("FavoritePayment(): error = %v", err)
+	}
+
+	if _, err := s.PayFromFavorite(favorite.ID); err != ErrNotEnoughBalance {
+		t.Errorf("PayFromFavorite(): error = %v, want %v", err, ErrNotEnoughBalance)
+	}
+}
+
+func TestService_BalanceAt_beforeDeposit(t *testing.T) {
+	s := newTestService()
+	account, _, err := s.addAccount(defaultTestAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := s.BalanceAt(account.ID, time.Now().Add(-time.Hour))
+	if got != 0 {
+		t.Errorf("BalanceAt(): got = %v, want 0", got)
+	}
+}
@@ -0,0 +1,82 @@
+package wallet
+
+import "github.com/delgoden/wallet/pkg/types"
+
+// MemoryRepository is a Repository backed by plain in-process slices. It is
+// the default Repository used when Service is created without one, and
+// keeps no state across process restarts.
+type MemoryRepository struct {
+	accounts     []*types.Account
+	payments     []*types.Payment
+	favorites    []*types.Favorite
+	transactions []*types.Transaction
+}
+
+// NewMemoryRepository creates an empty MemoryRepository.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{}
+}
+
+// SaveAccount inserts account, or overwrites the existing entry with the same ID.
+func (r *MemoryRepository) SaveAccount(account *types.Account) error {
+	for i, acc := range r.accounts {
+		if acc.ID == account.ID {
+			r.accounts[i] = account
+			return nil
+		}
+	}
+	r.accounts = append(r.accounts, account)
+	return nil
+}
+
+// LoadAccounts returns all saved accounts.
+func (r *MemoryRepository) LoadAccounts() ([]*types.Account, error) {
+	return r.accounts, nil
+}
+
+// SavePayment inserts payment, or overwrites the existing entry with the same ID.
+func (r *MemoryRepository) SavePayment(payment *types.Payment) error {
+	for i, pay := range r.payments {
+		if pay.ID == payment.ID {
+			r.payments[i] = payment
+			return nil
+		}
+	}
+	r.payments = append(r.payments, payment)
+	return nil
+}
+
+// LoadPayments returns all saved payments.
+func (r *MemoryRepository) LoadPayments() ([]*types.Payment, error) {
+	return r.payments, nil
+}
+
+// SaveFavorite inserts favorite, or overwrites the existing entry with the same ID.
+func (r *MemoryRepository) SaveFavorite(favorite *types.Favorite) error {
+	for i, fvr := range r.favorites {
+		if fvr.ID == favorite.ID {
+			r.favorites[i] = favorite
+			return nil
+		}
+	}
+	r.favorites = append(r.favorites, favorite)
+	return nil
+}
+
+// LoadFavorites returns all saved favorites.
+func (r *MemoryRepository) LoadFavorites() ([]*types.Favorite, error) {
+	return r.favorites, nil
+}
+
+// SaveTransaction appends transaction to the ledger. Transactions are
+// immutable once recorded, so unlike the other Save methods this never
+// overwrites an existing entry.
+func (r *MemoryRepository) SaveTransaction(transaction *types.Transaction) error {
+	r.transactions = append(r.transactions, transaction)
+	return nil
+}
+
+// LoadTransactions returns every recorded transaction.
+func (r *MemoryRepository) LoadTransactions() ([]*types.Transaction, error) {
+	return r.transactions, nil
+}
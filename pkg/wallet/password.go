@@ -0,0 +1,126 @@
+package wallet
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// minPasswordScore is the lowest passwordScore RegisterAccount accepts.
+const minPasswordScore = 2
+
+// minPasswordLength is the shortest password that can reach a score above 1,
+// regardless of character variety: a short password is guessable by brute
+// force no matter how many character classes it mixes.
+const minPasswordLength = 8
+
+// commonPasswords is a small denylist of frequently-reused passwords; a
+// match forces the weakest possible score regardless of character variety.
+var commonPasswords = map[string]struct{}{
+	"password":  {},
+	"123456":    {},
+	"12345678":  {},
+	"123456789": {},
+	"qwerty":    {},
+	"111111":    {},
+	"abc123":    {},
+	"password1": {},
+	"admin":     {},
+	"letmein":   {},
+}
+
+// passwordScore estimates password strength on zxcvbn's familiar 0-4 scale.
+// It is a coarse approximation, not a full zxcvbn port: zxcvbn buckets
+// guesses at 10^3, 10^6, 10^8 and 10^10; this combines character-class
+// entropy with a common-password check and buckets the resulting guess
+// count the same way.
+func passwordScore(password string) int {
+	if _, common := commonPasswords[strings.ToLower(password)]; common {
+		return 0
+	}
+
+	var hasLower, hasUpper, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		default:
+			hasSymbol = true
+		}
+	}
+
+	charset := 0
+	if hasLower {
+		charset += 26
+	}
+	if hasUpper {
+		charset += 26
+	}
+	if hasDigit {
+		charset += 10
+	}
+	if hasSymbol {
+		charset += 33
+	}
+	if charset == 0 {
+		return 0
+	}
+
+	bits := float64(effectiveLength(password)) * math.Log2(float64(charset))
+	guesses := math.Pow(2, bits) / 2
+
+	score := 0
+	switch {
+	case guesses < 1e3:
+		score = 0
+	case guesses < 1e6:
+		score = 1
+	case guesses < 1e8:
+		score = 2
+	case guesses < 1e10:
+		score = 3
+	default:
+		score = 4
+	}
+
+	if len(password) < minPasswordLength && score > 1 {
+		score = 1
+	}
+	return score
+}
+
+// effectiveLength returns password's length with repeated and sequential
+// runs (e.g. "aaaa", "1234", "dcba") collapsed, so they contribute at most 2
+// characters of entropy each: after the first two characters of such a run,
+// every further one is guessable from the pattern rather than adding new
+// information.
+func effectiveLength(password string) int {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return 0
+	}
+
+	length := 1
+	runLength := 1
+	var runDelta rune
+	for i := 1; i < len(runes); i++ {
+		delta := runes[i] - runes[i-1]
+		continuesRun := delta == 0 || delta == 1 || delta == -1
+		if continuesRun && (runLength == 1 || delta == runDelta) {
+			runLength++
+			runDelta = delta
+		} else {
+			runLength = 1
+			runDelta = 0
+		}
+
+		if runLength <= 2 {
+			length++
+		}
+	}
+	return length
+}
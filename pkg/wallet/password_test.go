@@ -0,0 +1,43 @@
+package wallet
+
+import "testing"
+
+func TestPasswordScore_commonPassword(t *testing.T) {
+	if got := passwordScore("password1"); got != 0 {
+		t.Errorf("passwordScore(%q) = %v, want 0", "password1", got)
+	}
+}
+
+func TestPasswordScore_repeatedCharacterScoresLow(t *testing.T) {
+	got := passwordScore("aaaaaaaaaa")
+	if got >= minPasswordScore {
+		t.Errorf("passwordScore(%q) = %v, want < %v", "aaaaaaaaaa", got, minPasswordScore)
+	}
+}
+
+func TestPasswordScore_sequentialRunScoresLow(t *testing.T) {
+	got := passwordScore("abcdefgh12")
+	if got >= minPasswordScore {
+		t.Errorf("passwordScore(%q) = %v, want < %v", "abcdefgh12", got, minPasswordScore)
+	}
+}
+
+func TestPasswordScore_shortMixedCharsetScoresLow(t *testing.T) {
+	got := passwordScore("aB3!")
+	if got >= minPasswordScore {
+		t.Errorf("passwordScore(%q) = %v, want < %v", "aB3!", got, minPasswordScore)
+	}
+}
+
+func TestPasswordScore_longMixedCharsetScoresHigh(t *testing.T) {
+	got := passwordScore("Tr0ub4dor&3")
+	if got < minPasswordScore {
+		t.Errorf("passwordScore(%q) = %v, want >= %v", "Tr0ub4dor&3", got, minPasswordScore)
+	}
+}
+
+func TestPasswordScore_empty(t *testing.T) {
+	if got := passwordScore(""); got != 0 {
+		t.Errorf("passwordScore(\"\") = %v, want 0", got)
+	}
+}
@@ -0,0 +1,16 @@
+package wallet
+
+import "github.com/delgoden/wallet/pkg/types"
+
+// Repository abstracts how accounts, payments and favorites are persisted,
+// letting Service remain storage-agnostic.
+type Repository interface {
+	SaveAccount(account *types.Account) error
+	LoadAccounts() ([]*types.Account, error)
+	SavePayment(payment *types.Payment) error
+	LoadPayments() ([]*types.Payment, error)
+	SaveFavorite(favorite *types.Favorite) error
+	LoadFavorites() ([]*types.Favorite, error)
+	SaveTransaction(transaction *types.Transaction) error
+	LoadTransactions() ([]*types.Transaction, error)
+}
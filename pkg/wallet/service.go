@@ -1,22 +1,148 @@
 package wallet
 
 import (
-	"bufio"
 	"errors"
+	"sync"
+	"time"
+
 	"github.com/delgoden/wallet/pkg/types"
 	"github.com/google/uuid"
-	"io"
-	"log"
-	"os"
-	"strconv"
-	"strings"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// maxCredentialLength bounds phone and password length on registration so
+// an oversized input can't be used to burn CPU in bcrypt or storage.
+const maxCredentialLength = 1024
+
 type Service struct {
-	nextAccountID int64
-	accounts      []*types.Account
-	payments      []*types.Payment
-	favorites     []*types.Favorite
+	mu                sync.RWMutex
+	nextAccountID     int64
+	accounts          []*types.Account
+	payments          []*types.Payment
+	favorites         []*types.Favorite
+	transactions      []*types.Transaction
+	repo              Repository
+	rates             map[types.Currency]map[types.Currency]float64
+	preferredCurrency types.Currency
+}
+
+// NewService creates a Service backed by repo, hydrating its in-memory
+// accounts, payments, favorites and ledger from whatever repo already holds.
+func NewService(repo Repository) (*Service, error) {
+	s := &Service{repo: repo}
+
+	accounts, err := repo.LoadAccounts()
+	if err != nil {
+		return nil, err
+	}
+	s.accounts = accounts
+	for _, account := range accounts {
+		if account.ID > s.nextAccountID {
+			s.nextAccountID = account.ID
+		}
+	}
+
+	payments, err := repo.LoadPayments()
+	if err != nil {
+		return nil, err
+	}
+	s.payments = payments
+
+	favorites, err := repo.LoadFavorites()
+	if err != nil {
+		return nil, err
+	}
+	s.favorites = favorites
+
+	transactions, err := repo.LoadTransactions()
+	if err != nil {
+		return nil, err
+	}
+	s.transactions = transactions
+
+	for _, account := range s.accounts {
+		account.Balance = s.balance(account.ID)
+	}
+
+	return s, nil
+}
+
+// repository returns the Repository backing s, defaulting to an in-memory
+// one so a zero-value Service keeps working as before.
+func (s *Service) repository() Repository {
+	if s.repo == nil {
+		s.repo = NewMemoryRepository()
+	}
+	return s.repo
+}
+
+// recordTransaction appends a ledger entry transferring amount from debit to
+// credit (an account ID of 0 stands for a party outside the ledger) and
+// persists it. Transactions are the source of truth for account balances;
+// see Service.balance and Service.BalanceAt.
+func (s *Service) recordTransaction(debit, credit int64, amount types.Money, currency types.Currency, reference, note string) (*types.Transaction, error) {
+	transaction := &types.Transaction{
+		ID:        uuid.New().String(),
+		Debit:     debit,
+		Credit:    credit,
+		Amount:    amount,
+		Currency:  currency,
+		Timestamp: time.Now(),
+		Reference: reference,
+		Note:      note,
+	}
+	s.transactions = append(s.transactions, transaction)
+
+	if err := s.repository().SaveTransaction(transaction); err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// balance sums every ledger entry crediting or debiting accountID.
+func (s *Service) balance(accountID int64) types.Money {
+	return s.balanceAt(accountID, time.Now())
+}
+
+// balanceAt sums every ledger entry crediting or debiting accountID that was
+// recorded at or before at.
+func (s *Service) balanceAt(accountID int64, at time.Time) types.Money {
+	var total types.Money
+	for _, transaction := range s.transactions {
+		if transaction.Timestamp.After(at) {
+			continue
+		}
+		if transaction.Credit == accountID {
+			total += transaction.Amount
+		}
+		if transaction.Debit == accountID {
+			total -= transaction.Amount
+		}
+	}
+	return total
+}
+
+// Ledger returns every transaction that credits or debits accountID, in the
+// order they were recorded.
+func (s *Service) Ledger(accountID int64) []types.Transaction {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var ledger []types.Transaction
+	for _, transaction := range s.transactions {
+		if transaction.Debit == accountID || transaction.Credit == accountID {
+			ledger = append(ledger, *transaction)
+		}
+	}
+	return ledger
+}
+
+// BalanceAt returns accountID's balance as of at, derived from the ledger.
+func (s *Service) BalanceAt(accountID int64, at time.Time) types.Money {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.balanceAt(accountID, at)
 }
 
 var (
@@ -25,34 +151,101 @@ var (
 	ErrAccountNotFound      = errors.New("account not found")
 	ErrNotEnoughBalance     = errors.New("not enough balance")
 	ErrPaymentNotFound      = errors.New("payment not found")
-	ErrFavoriteNotFound = errors.New("favorite not found")
+	ErrFavoriteNotFound     = errors.New("favorite not found")
+	ErrWeakPassword         = errors.New("password is too weak")
+	ErrCredentialsTooLong   = errors.New("phone or password is too long")
+	ErrInvalidCredentials   = errors.New("phone or password is incorrect")
+	ErrInvalidRate          = errors.New("rate must be greater than zero")
+	ErrUnknownCurrencyRate  = errors.New("no conversion rate known for currency pair")
 )
 
-// RegisterAccount provides a method for adding new accounts
-func (s *Service) RegisterAccount(phone types.Phone) (*types.Account, error) {
+// RegisterAccount provides a method for adding new accounts. currency sets
+// the account's native currency that Deposit and Pay convert into; if empty
+// it defaults to the service's preferred currency.
+func (s *Service) RegisterAccount(phone types.Phone, password string, currency types.Currency) (*types.Account, error) {
+	if len(phone) > maxCredentialLength || len(password) > maxCredentialLength {
+		return nil, ErrCredentialsTooLong
+	}
+
+	if passwordScore(password) < minPasswordScore {
+		return nil, ErrWeakPassword
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	for _, account := range s.accounts {
 		if account.Phone == phone {
 			return nil, ErrPhoneRegistered
 		}
 	}
 
+	if currency == "" {
+		currency = s.preferredCurrencyLocked()
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
 	s.nextAccountID++
 	account := &types.Account{
-		ID:      s.nextAccountID,
-		Phone:   phone,
-		Balance: 0,
+		ID:           s.nextAccountID,
+		Phone:        phone,
+		Balance:      0,
+		Currency:     currency,
+		PasswordHash: string(hash),
 	}
 	s.accounts = append(s.accounts, account)
 
+	if err := s.repository().SaveAccount(account); err != nil {
+		return nil, err
+	}
+
 	return account, nil
 }
 
-// Deposit provides a method to process balance replenishment
-func (s *Service) Deposit(accountID int64, amount types.Money) error {
+// Authenticate verifies phone and password against a registered account.
+func (s *Service) Authenticate(phone types.Phone, password string) (*types.Account, error) {
+	if len(phone) > maxCredentialLength || len(password) > maxCredentialLength {
+		return nil, ErrCredentialsTooLong
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var account *types.Account
+	for _, acc := range s.accounts {
+		if acc.Phone == phone {
+			account = acc
+			break
+		}
+	}
+
+	if account == nil {
+		return nil, ErrAccountNotFound
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(account.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	return account, nil
+}
+
+// Deposit provides a method to process balance replenishment. amount is
+// given in currency and converted into the account's native currency. The
+// deposit is recorded as a ledger entry crediting accountID from outside the
+// ledger; see Service.Ledger.
+func (s *Service) Deposit(accountID int64, amount types.Money, currency types.Currency) error {
 	if amount <= 0 {
 		return ErrAmountMustBePositive
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	var account *types.Account
 	for _, acc := range s.accounts {
 		if acc.ID == accountID {
@@ -65,16 +258,32 @@ func (s *Service) Deposit(accountID int64, amount types.Money) error {
 		return ErrAccountNotFound
 	}
 
-	account.Balance += amount
-	return nil
+	converted, err := s.convert(amount, currency, account.Currency)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.recordTransaction(0, account.ID, converted, account.Currency, "", "deposit"); err != nil {
+		return err
+	}
+
+	account.Balance = s.balance(account.ID)
+	return s.repository().SaveAccount(account)
 }
 
-// Pay provides a payment processing method
-func (s *Service) Pay(accountID int64, amount types.Money, category types.PaymentCategory) (*types.Payment, error) {
+// Pay provides a payment processing method. amount is given in currency and
+// converted into the account's native currency before the balance check and
+// debit; the resulting Payment records the converted amount and currency.
+// The debit is recorded as a ledger entry debiting accountID to outside the
+// ledger, referencing the new payment; see Service.Ledger.
+func (s *Service) Pay(accountID int64, amount types.Money, currency types.Currency, category types.PaymentCategory) (*types.Payment, error) {
 	if amount <= 0 {
 		return nil, ErrAmountMustBePositive
 	}
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	var account *types.Account
 	for _, acc := range s.accounts {
 		if acc.ID == accountID {
@@ -87,26 +296,44 @@ func (s *Service) Pay(accountID int64, amount types.Money, category types.Paymen
 		return nil, ErrAccountNotFound
 	}
 
-	if account.Balance < amount {
+	converted, err := s.convert(amount, currency, account.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.balance(account.ID) < converted {
 		return nil, ErrNotEnoughBalance
 	}
 
-	account.Balance -= amount
 	paymentID := uuid.New().String()
 	payment := &types.Payment{
 		ID:        paymentID,
 		AccountID: accountID,
-		Amount:    amount,
+		Amount:    converted,
+		Currency:  account.Currency,
 		Category:  category,
 		Status:    types.PaymentStatusInProgress,
 	}
 	s.payments = append(s.payments, payment)
 
+	if _, err := s.recordTransaction(account.ID, 0, converted, account.Currency, paymentID, "payment"); err != nil {
+		return nil, err
+	}
+	account.Balance = s.balance(account.ID)
+
+	if err := s.repository().SaveAccount(account); err != nil {
+		return nil, err
+	}
+	if err := s.repository().SavePayment(payment); err != nil {
+		return nil, err
+	}
+
 	return payment, nil
 }
 
-// FindAccountByID search for an account by ID
-func (s *Service) FindAccountByID(accountID int64) (*types.Account, error) {
+// findAccountByID is FindAccountByID without locking, for callers that
+// already hold s.mu.
+func (s *Service) findAccountByID(accountID int64) (*types.Account, error) {
 	var account *types.Account
 	for _, acc := range s.accounts {
 		if acc.ID == accountID {
@@ -122,8 +349,17 @@ func (s *Service) FindAccountByID(accountID int64) (*types.Account, error) {
 	return account, nil
 }
 
-// FindPaymentByID search for a payment by ID
-func (s *Service) FindPaymentByID(paymentID string) (*types.Payment, error) {
+// FindAccountByID search for an account by ID
+func (s *Service) FindAccountByID(accountID int64) (*types.Account, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.findAccountByID(accountID)
+}
+
+// findPaymentByID is FindPaymentByID without locking, for callers that
+// already hold s.mu.
+func (s *Service) findPaymentByID(paymentID string) (*types.Payment, error) {
 	var payment *types.Payment
 	for _, pay := range s.payments {
 		if pay.ID == paymentID {
@@ -139,22 +375,46 @@ func (s *Service) FindPaymentByID(paymentID string) (*types.Payment, error) {
 	return payment, nil
 }
 
-// Reject cancels the payment and returns the money to the balance
+// FindPaymentByID search for a payment by ID
+func (s *Service) FindPaymentByID(paymentID string) (*types.Payment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.findPaymentByID(paymentID)
+}
+
+// Reject cancels the payment and returns the money to the balance. It is
+// safe to call more than once for the same payment: a payment already in
+// PaymentStatusFail is left alone instead of being refunded again.
 func (s *Service) Reject(paymentID string) error {
-	payment, err := s.FindPaymentByID(paymentID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payment, err := s.findPaymentByID(paymentID)
 	if err != nil {
 		return err
 	}
 
-	payment.Status = types.PaymentStatusFail
+	if payment.Status == types.PaymentStatusFail {
+		return nil
+	}
 
-	account, err1 := s.FindAccountByID(payment.AccountID)
+	account, err := s.findAccountByID(payment.AccountID)
 	if err != nil {
-		return err1
+		return err
 	}
 
-	account.Balance += payment.Amount
-	return nil
+	payment.Status = types.PaymentStatusFail
+
+	if _, err := s.recordTransaction(0, account.ID, payment.Amount, payment.Currency, paymentID, "reject refund"); err != nil {
+		return err
+	}
+	account.Balance = s.balance(account.ID)
+
+	if err := s.repository().SavePayment(payment); err != nil {
+		return err
+	}
+	return s.repository().SaveAccount(account)
 }
 
 // Repeat allows the ID to repeat the payment
@@ -164,7 +424,7 @@ func (s *Service) Repeat(paymentID string) (*types.Payment, error) {
 		return nil, err
 	}
 
-	repeatPayment, err := s.Pay(payment.AccountID, payment.Amount, payment.Category)
+	repeatPayment, err := s.Pay(payment.AccountID, payment.Amount, payment.Currency, payment.Category)
 	if err != nil {
 		return nil, err
 	}
@@ -174,7 +434,10 @@ func (s *Service) Repeat(paymentID string) (*types.Payment, error) {
 
 // FavoritePayment creates favorites from a specific payment
 func (s *Service) FavoritePayment(paymentID string, name string) (*types.Favorite, error) {
-	payment, err := s.FindPaymentByID(paymentID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	payment, err := s.findPaymentByID(paymentID)
 	if err != nil {
 		return nil, err
 	}
@@ -185,34 +448,74 @@ func (s *Service) FavoritePayment(paymentID string, name string) (*types.Favorit
 		AccountID: payment.AccountID,
 		Name:      name,
 		Amount:    payment.Amount,
+		Currency:  payment.Currency,
 		Category:  payment.Category,
 	}
 	s.favorites = append(s.favorites, favorite)
 
+	if err := s.repository().SaveFavorite(favorite); err != nil {
+		return nil, err
+	}
+
 	return favorite, nil
 }
 
-// PayFromFavorite makes a payment from a specific favorite
+// PayFromFavorite makes a payment from a specific favorite. It debits the
+// account exactly like Pay: the amount is converted into the account's
+// native currency, checked against the ledger balance, and recorded as a
+// ledger entry debiting the account to outside the ledger.
 func (s *Service) PayFromFavorite(favoriteID string) (*types.Payment, error) {
-	favorite, err := s.FindFavoriteByID(favoriteID)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	favorite, err := s.findFavoriteByID(favoriteID)
+	if err != nil {
+		return nil, err
+	}
+
+	account, err := s.findAccountByID(favorite.AccountID)
 	if err != nil {
 		return nil, err
 	}
 
+	converted, err := s.convert(favorite.Amount, favorite.Currency, account.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.balance(account.ID) < converted {
+		return nil, ErrNotEnoughBalance
+	}
+
 	paymentID := uuid.New().String()
 	payment := &types.Payment{
 		ID:        paymentID,
 		AccountID: favorite.AccountID,
-		Amount:    favorite.Amount,
+		Amount:    converted,
+		Currency:  account.Currency,
 		Category:  favorite.Category,
 		Status:    types.PaymentStatusInProgress,
 	}
 	s.payments = append(s.payments, payment)
+
+	if _, err := s.recordTransaction(account.ID, 0, converted, account.Currency, paymentID, "payment"); err != nil {
+		return nil, err
+	}
+	account.Balance = s.balance(account.ID)
+
+	if err := s.repository().SaveAccount(account); err != nil {
+		return nil, err
+	}
+	if err := s.repository().SavePayment(payment); err != nil {
+		return nil, err
+	}
+
 	return payment, nil
 }
 
-// FindFavoriteByID search gor an favorite by ID
-func (s *Service) FindFavoriteByID(favoriteID string) (*types.Favorite, error) {
+// findFavoriteByID is FindFavoriteByID without locking, for callers that
+// already hold s.mu.
+func (s *Service) findFavoriteByID(favoriteID string) (*types.Favorite, error) {
 	var favorite *types.Favorite
 	for _, fvr := range s.favorites {
 		if fvr.ID == favoriteID {
@@ -228,282 +531,109 @@ func (s *Service) FindFavoriteByID(favoriteID string) (*types.Favorite, error) {
 	return favorite, nil
 }
 
-// ExportToFile exports all accounts to a file
-func (s *Service) ExportToFile(path string) error {
-	file, err := os.Create(path)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		err := file.Close()
-		if err != nil {
-			log.Print(err)
-		}
-	}()
-
-	for _, account := range s.accounts {
-		accStr := strconv.FormatInt(account.ID, 10) + ";" + string(account.Phone) + ";" + strconv.FormatInt(int64(account.Balance), 10) + "|"
-		_, err := file.Write([]byte(accStr))
-		if err != nil {
-			return err
-		}
-	}
+// FindFavoriteByID search gor an favorite by ID
+func (s *Service) FindFavoriteByID(favoriteID string) (*types.Favorite, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	return err
+	return s.findFavoriteByID(favoriteID)
 }
 
-// ImportFromFile imports all accounts from a file
-func (s *Service) ImportFromFile(path string) error {
-	file, err := os.Open(path)
+// Export saves all accounts, payments, favorites and ledger transactions as
+// JSON documents under dir, using a FileRepository. It replaces the old
+// `;`/`|`-delimited dump format, which silently corrupted on phone numbers or
+// notes containing those separators.
+func (s *Service) Export(dir string) error {
+	repo, err := NewFileRepository(dir)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		err := file.Close()
-		if err != nil {
-			log.Print(err)
-		}
-	}()
 
-	reader := bufio.NewReader(file)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-	for {
-		accStr, err := reader.ReadString('|')
-		if err == io.EOF {
-			break
+	for _, account := range s.accounts {
+		if err := repo.SaveAccount(account); err != nil {
+			return err
 		}
-		if err != nil {
+	}
+	for _, payment := range s.payments {
+		if err := repo.SavePayment(payment); err != nil {
 			return err
 		}
-		accSls := strings.Split(accStr, ";")
-		ID, err := strconv.Atoi(accSls[0])
-		if err != nil {
+	}
+	for _, favorite := range s.favorites {
+		if err := repo.SaveFavorite(favorite); err != nil {
 			return err
 		}
-		Balance, err := strconv.Atoi(strings.TrimSuffix(accSls[2], "|"))
-		if err != nil {
+	}
+	for _, transaction := range s.transactions {
+		if err := repo.SaveTransaction(transaction); err != nil {
 			return err
 		}
-		s.accounts = append(s.accounts, &types.Account{ID: int64(ID), Phone: types.Phone(accSls[1]), Balance: types.Money(Balance)})
 	}
-	return err
+
+	return nil
 }
 
-// Export export of all accounts, payments and favorites to files
-func (s *Service) Export(dir string) error {
-	err := os.MkdirAll(dir, 0777)
+// Import loads accounts, payments, favorites and ledger transactions
+// previously written by Export, skipping any that are already present in s.
+func (s *Service) Import(dir string) error {
+	repo, err := NewFileRepository(dir)
 	if err != nil {
-		panic(err)
-	}
-	if len(s.accounts) != 0 {
-		accFile, err := os.Create(dir + "/accounts.dump")
-		if err != nil {
-			log.Print(err)
-		}
-		defer func() {
-			if cerr := accFile.Close(); cerr != nil {
-				if err == nil {
-					log.Print(err)
-				}
-			}
-		}()
-
-		for _, account := range s.accounts {
-			accStr := strconv.FormatInt(account.ID, 10) + ";" + string(account.Phone) + ";" + strconv.FormatInt(int64(account.Balance), 10) + "\n"
-			_, err := accFile.Write([]byte(accStr))
-			if err != nil {
-				return err
-			}
-		}
+		return err
 	}
 
-	if len(s.payments) != 0 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-		payFile, err := os.Create(dir + "/payments.dump")
-		if err != nil {
-			log.Print(err)
-		}
-		defer func() {
-			if cerr := payFile.Close(); cerr != nil {
-				if err == nil {
-					log.Print(err)
-				}
-			}
-		}()
-
-		for _, payment := range s.payments {
-			payStr := payment.ID + ";" + strconv.FormatInt(payment.AccountID, 10) + ";" + strconv.FormatInt(int64(payment.Amount), 10) + ";" + string(payment.Category) + ";" + string(payment.Status) + "\n"
-			_, err := payFile.WriteString(payStr)
-			if err != nil {
-				return err
-			}
-		}
+	accounts, err := repo.LoadAccounts()
+	if err != nil {
+		return err
 	}
-
-	if len(s.favorites) != 0 {
-
-		favFile, err := os.Create(dir + "/favorites.dump")
-		if err != nil {
-			log.Print(err)
-		}
-		defer func() {
-			if cerr := favFile.Close(); cerr != nil {
-				if err == nil {
-					log.Print(err)
-				}
-			}
-		}()
-
-		for _, favorite := range s.favorites {
-			favStr := favorite.ID + ";" + strconv.FormatInt(favorite.AccountID, 10) + ";" + favorite.Name + ";" + strconv.FormatInt(int64(favorite.Amount), 10) + ";" + string(favorite.Category) + "\n"
-			_, err := favFile.WriteString(favStr)
-			if err != nil {
-				return err
-			}
+	for _, account := range accounts {
+		if _, err := s.findAccountByID(account.ID); err != nil {
+			s.accounts = append(s.accounts, account)
+			s.nextAccountID = account.ID
 		}
 	}
 
-	return nil
-}
-
-// Import import of all accounts, payments and favorites from files
-func (s *Service) Import(dir string) error {
-	file, err := os.Open(dir + "/accounts.dump")
-	if err == nil || errors.Is(err, os.ErrExist) {
-		reader := bufio.NewReader(file)
-		for {
-			accStr, err := reader.ReadString('\n')
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				return err
-			}
-			accSls := strings.Split(strings.TrimSuffix(accStr, "\n"), ";")
-			ID, err := strconv.Atoi(accSls[0])
-			if err != nil {
-				return err
-			}
-			Balance, err := strconv.Atoi(accSls[2])
-			if err != nil {
-				return err
-			}
-			_, err = s.FindAccountByID(int64(ID))
-			if err != nil {
-				account := &types.Account{
-					ID:      int64(ID),
-					Phone:   types.Phone(accSls[1]),
-					Balance: types.Money(Balance),
-				}
-				s.accounts = append(s.accounts, account)
-				s.nextAccountID = int64(ID)
-			}
-
-		}
-	} else {
-		log.Print(err)
+	payments, err := repo.LoadPayments()
+	if err != nil {
+		return err
 	}
-
-	defer func() {
-		err := file.Close()
-		if err != nil {
-			log.Print(err)
+	for _, payment := range payments {
+		if _, err := s.findPaymentByID(payment.ID); err != nil {
+			s.payments = append(s.payments, payment)
 		}
-	}()
-
-	payFile, err := os.Open(dir + "/payments.dump")
-	if err == nil || errors.Is(err, os.ErrExist) {
-		reader := bufio.NewReader(payFile)
-		for {
-			payStr, err := reader.ReadString('\n')
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				return err
-			}
-			paySls := strings.Split(strings.TrimSuffix(payStr, "\n"), ";")
-			payID := paySls[0]
-			if err != nil {
-				return err
-			}
-			AccountID, err := strconv.Atoi(paySls[1])
-			if err != nil {
-				return err
-			}
-			Amount, err := strconv.Atoi(paySls[2])
-			if err != nil {
-				return err
-			}
-			_, err = s.FindPaymentByID(payID)
-			if err != nil {
-				payment := &types.Payment{
-					ID:        payID,
-					AccountID: int64(AccountID),
-					Amount:    types.Money(Amount),
-					Category:  types.PaymentCategory(paySls[3]),
-					Status:    types.PaymentStatus(paySls[4]),
-				}
-				s.payments = append(s.payments, payment)
-			}
-		}
-	} else {
-		log.Print(err)
 	}
 
-	defer func() {
-		err := payFile.Close()
-		if err != nil {
-			log.Print(err)
-		}
-	}()
-
-	favFile, err := os.Open(dir + "/favorites.dump")
-	if err == nil || errors.Is(err, os.ErrExist) {
-		reader := bufio.NewReader(favFile)
-		for {
-			favStr, err := reader.ReadString('\n')
-			if err == io.EOF {
-				break
-			}
-			if err != nil {
-				return err
-			}
-			favSls := strings.Split(strings.TrimSuffix(favStr, "\n"), ";")
-			favID := favSls[0]
-			if err != nil {
-				return err
-			}
-			AccountID, err := strconv.Atoi(favSls[1])
-			if err != nil {
-				return err
-			}
-			Amount, err := strconv.Atoi(favSls[3])
-			if err != nil {
-				return err
-			}
-			_, err = s.FindFavoriteByID(favID)
-			if err != nil {
-				favorite := &types.Favorite{
-					ID:        favID,
-					AccountID: int64(AccountID),
-					Name:      favSls[2],
-					Amount:    types.Money(Amount),
-					Category:  types.PaymentCategory(favSls[4]),
-				}
-				s.favorites = append(s.favorites, favorite)
-			}
+	favorites, err := repo.LoadFavorites()
+	if err != nil {
+		return err
+	}
+	for _, favorite := range favorites {
+		if _, err := s.findFavoriteByID(favorite.ID); err != nil {
+			s.favorites = append(s.favorites, favorite)
 		}
-	} else {
-		log.Print(err)
 	}
 
-	defer func() {
-		err := favFile.Close()
-		if err != nil {
-			log.Print(err)
+	transactions, err := repo.LoadTransactions()
+	if err != nil {
+		return err
+	}
+	known := make(map[string]struct{}, len(s.transactions))
+	for _, transaction := range s.transactions {
+		known[transaction.ID] = struct{}{}
+	}
+	for _, transaction := range transactions {
+		if _, ok := known[transaction.ID]; !ok {
+			s.transactions = append(s.transactions, transaction)
 		}
-	}()
+	}
+	for _, account := range s.accounts {
+		account.Balance = s.balance(account.ID)
+	}
 
 	return nil
-}
\ No newline at end of file
+}
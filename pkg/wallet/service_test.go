@@ -13,6 +13,8 @@ type testService struct {
 
 type testAccount struct {
 	phone    types.Phone
+	password string
+	currency types.Currency
 	balance  types.Money
 	payments []struct {
 		amount   types.Money
@@ -21,8 +23,10 @@ type testAccount struct {
 }
 
 var defaultTestAccount = testAccount{
-	phone:   "992000000001",
-	balance: 10_000_000_000_00,
+	phone:    "992000000001",
+	password: "Tr0ub4dor&3",
+	currency: types.TJS,
+	balance:  10_000_000_000_00,
 	payments: []struct {
 		amount   types.Money
 		category types.PaymentCategory
@@ -36,19 +40,19 @@ func newTestService() *testService {
 }
 
 func (s *testService) addAccount(data testAccount) (*types.Account, []*types.Payment, error) {
-	account, err := s.RegisterAccount(data.phone)
+	account, err := s.RegisterAccount(data.phone, data.password, data.currency)
 	if err != nil {
 		return nil, nil, fmt.Errorf("can't register account, error = %v", err)
 	}
 
-	err = s.Deposit(account.ID, data.balance)
+	err = s.Deposit(account.ID, data.balance, data.currency)
 	if err != nil {
 		return nil, nil, fmt.Errorf("can't deposity account, error = %v", err)
 	}
 
 	payments := make([]*types.Payment, len(data.payments))
 	for i, payment := range data.payments {
-		payments[i], err = s.Pay(account.ID, payment.amount, payment.category)
+		payments[i], err = s.Pay(account.ID, payment.amount, data.currency, payment.category)
 		if err != nil {
 			return nil, nil, fmt.Errorf("can't make payment, error = %v", err)
 		}
@@ -89,3 +93,56 @@ func TestService_FindAccountByID_failed(t *testing.T) {
 	}
 
 }
+
+func TestService_RegisterAccount_weakPassword(t *testing.T) {
+	s := newTestService()
+	_, err := s.RegisterAccount("992000000001", "12345", types.TJS)
+	if err != ErrWeakPassword {
+		t.Errorf("RegisterAccount(): error = %v, want %v", err, ErrWeakPassword)
+	}
+}
+
+func TestService_RegisterAccount_credentialsTooLong(t *testing.T) {
+	s := newTestService()
+	longPassword := make([]byte, maxCredentialLength+1)
+	for i := range longPassword {
+		longPassword[i] = 'a'
+	}
+
+	_, err := s.RegisterAccount("992000000001", string(longPassword), types.TJS)
+	if err != ErrCredentialsTooLong {
+		t.Errorf("RegisterAccount(): error = %v, want %v", err, ErrCredentialsTooLong)
+	}
+}
+
+func TestService_Authenticate_success(t *testing.T) {
+	s := newTestService()
+	account, _, err := s.addAccount(defaultTestAccount)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	got, err := s.Authenticate(account.Phone, defaultTestAccount.password)
+	if err != nil {
+		t.Errorf("Authenticate(): error = %v", err)
+		return
+	}
+	if got.ID != account.ID {
+		t.Errorf("Authenticate(): wrong account returned = %v", got)
+	}
+}
+
+func TestService_Authenticate_wrongPassword(t *testing.T) {
+	s := newTestService()
+	account, _, err := s.addAccount(defaultTestAccount)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	_, err = s.Authenticate(account.Phone, "wrong-password")
+	if err != ErrInvalidCredentials {
+		t.Errorf("Authenticate(): error = %v, want %v", err, ErrInvalidCredentials)
+	}
+}
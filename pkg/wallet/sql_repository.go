@@ -0,0 +1,229 @@
+package wallet
+
+import (
+	"time"
+
+	"github.com/delgoden/wallet/pkg/types"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// accountRecord is the GORM row mapping for types.Account.
+type accountRecord struct {
+	ID           int64 `gorm:"primaryKey"`
+	Phone        string
+	Balance      int64
+	Currency     string
+	PasswordHash string
+}
+
+// TableName pins the table name so it doesn't depend on GORM's pluralization.
+func (accountRecord) TableName() string { return "accounts" }
+
+// paymentRecord is the GORM row mapping for types.Payment.
+type paymentRecord struct {
+	ID        string `gorm:"primaryKey"`
+	AccountID int64
+	Amount    int64
+	Currency  string
+	Category  string
+	Status    string
+}
+
+func (paymentRecord) TableName() string { return "payments" }
+
+// favoriteRecord is the GORM row mapping for types.Favorite.
+type favoriteRecord struct {
+	ID        string `gorm:"primaryKey"`
+	AccountID int64
+	Name      string
+	Amount    int64
+	Currency  string
+	Category  string
+}
+
+func (favoriteRecord) TableName() string { return "favorites" }
+
+// transactionRecord is the GORM row mapping for types.Transaction.
+type transactionRecord struct {
+	ID        string `gorm:"primaryKey"`
+	Debit     int64
+	Credit    int64
+	Amount    int64
+	Currency  string
+	Timestamp time.Time
+	Reference string
+	Note      string
+}
+
+func (transactionRecord) TableName() string { return "transactions" }
+
+// SQLRepository is a Repository backed by a GORM database connection
+// (SQLite or Postgres). Call Migrate once before first use to create the
+// schema; see pkg/wallet/migrations for the equivalent raw SQL.
+type SQLRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLRepository wraps an already-opened GORM connection.
+func NewSQLRepository(db *gorm.DB) *SQLRepository {
+	return &SQLRepository{db: db}
+}
+
+// OpenSQLite opens a GORM connection to a SQLite database at path (use
+// ":memory:" for an ephemeral, in-process database).
+func OpenSQLite(path string) (*gorm.DB, error) {
+	return gorm.Open(sqlite.Open(path), &gorm.Config{})
+}
+
+// OpenPostgres opens a GORM connection to a Postgres database identified by dsn.
+func OpenPostgres(dsn string) (*gorm.DB, error) {
+	return gorm.Open(postgres.Open(dsn), &gorm.Config{})
+}
+
+// Migrate creates or updates the accounts, payments, favorites and
+// transactions tables.
+func (r *SQLRepository) Migrate() error {
+	return r.db.AutoMigrate(&accountRecord{}, &paymentRecord{}, &favoriteRecord{}, &transactionRecord{})
+}
+
+// SaveAccount inserts account, or overwrites the existing row with the same ID.
+func (r *SQLRepository) SaveAccount(account *types.Account) error {
+	record := accountRecord{
+		ID:           account.ID,
+		Phone:        string(account.Phone),
+		Balance:      int64(account.Balance),
+		Currency:     string(account.Currency),
+		PasswordHash: account.PasswordHash,
+	}
+	return r.db.Save(&record).Error
+}
+
+// LoadAccounts returns all saved accounts.
+func (r *SQLRepository) LoadAccounts() ([]*types.Account, error) {
+	var records []accountRecord
+	if err := r.db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*types.Account, 0, len(records))
+	for _, record := range records {
+		accounts = append(accounts, &types.Account{
+			ID:           record.ID,
+			Phone:        types.Phone(record.Phone),
+			Balance:      types.Money(record.Balance),
+			Currency:     types.Currency(record.Currency),
+			PasswordHash: record.PasswordHash,
+		})
+	}
+	return accounts, nil
+}
+
+// SavePayment inserts payment, or overwrites the existing row with the same ID.
+func (r *SQLRepository) SavePayment(payment *types.Payment) error {
+	record := paymentRecord{
+		ID:        payment.ID,
+		AccountID: payment.AccountID,
+		Amount:    int64(payment.Amount),
+		Currency:  string(payment.Currency),
+		Category:  string(payment.Category),
+		Status:    string(payment.Status),
+	}
+	return r.db.Save(&record).Error
+}
+
+// LoadPayments returns all saved payments.
+func (r *SQLRepository) LoadPayments() ([]*types.Payment, error) {
+	var records []paymentRecord
+	if err := r.db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	payments := make([]*types.Payment, 0, len(records))
+	for _, record := range records {
+		payments = append(payments, &types.Payment{
+			ID:        record.ID,
+			AccountID: record.AccountID,
+			Amount:    types.Money(record.Amount),
+			Currency:  types.Currency(record.Currency),
+			Category:  types.PaymentCategory(record.Category),
+			Status:    types.PaymentStatus(record.Status),
+		})
+	}
+	return payments, nil
+}
+
+// SaveFavorite inserts favorite, or overwrites the existing row with the same ID.
+func (r *SQLRepository) SaveFavorite(favorite *types.Favorite) error {
+	record := favoriteRecord{
+		ID:        favorite.ID,
+		AccountID: favorite.AccountID,
+		Name:      favorite.Name,
+		Amount:    int64(favorite.Amount),
+		Currency:  string(favorite.Currency),
+		Category:  string(favorite.Category),
+	}
+	return r.db.Save(&record).Error
+}
+
+// LoadFavorites returns all saved favorites.
+func (r *SQLRepository) LoadFavorites() ([]*types.Favorite, error) {
+	var records []favoriteRecord
+	if err := r.db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	favorites := make([]*types.Favorite, 0, len(records))
+	for _, record := range records {
+		favorites = append(favorites, &types.Favorite{
+			ID:        record.ID,
+			AccountID: record.AccountID,
+			Name:      record.Name,
+			Amount:    types.Money(record.Amount),
+			Currency:  types.Currency(record.Currency),
+			Category:  types.PaymentCategory(record.Category),
+		})
+	}
+	return favorites, nil
+}
+
+// SaveTransaction inserts transaction. Transactions are immutable once
+// recorded, so unlike the other Save methods this never overwrites an
+// existing row.
+func (r *SQLRepository) SaveTransaction(transaction *types.Transaction) error {
+	record := transactionRecord{
+		ID:        transaction.ID,
+		Debit:     transaction.Debit,
+		Credit:    transaction.Credit,
+		Amount:    int64(transaction.Amount),
+		Currency:  string(transaction.Currency),
+		Timestamp: transaction.Timestamp,
+		Reference: transaction.Reference,
+		Note:      transaction.Note,
+	}
+	return r.db.Create(&record).Error
+}
+
+// LoadTransactions returns every recorded transaction.
+func (r *SQLRepository) LoadTransactions() ([]*types.Transaction, error) {
+	var records []transactionRecord
+	if err := r.db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	transactions := make([]*types.Transaction, 0, len(records))
+	for _, record := range records {
+		transactions = append(transactions, &types.Transaction{
+			ID:        record.ID,
+			Debit:     record.Debit,
+			Credit:    record.Credit,
+			Amount:    types.Money(record.Amount),
+			Currency:  types.Currency(record.Currency),
+			Timestamp: record.Timestamp,
+			Reference: record.Reference,
+			Note:      record.Note,
+		})
+	}
+	return transactions, nil
+}
@@ -0,0 +1,100 @@
+package wallet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/delgoden/wallet/pkg/types"
+)
+
+func newTestSQLRepository(t *testing.T) *SQLRepository {
+	t.Helper()
+
+	db, err := OpenSQLite(":memory:")
+	if err != nil {
+		t.Fatalf("OpenSQLite(): error = %v", err)
+	}
+
+	repo := NewSQLRepository(db)
+	if err := repo.Migrate(); err != nil {
+		t.Fatalf("Migrate(): error = %v", err)
+	}
+	return repo
+}
+
+func TestSQLRepository_Accounts_roundtrip(t *testing.T) {
+	repo := newTestSQLRepository(t)
+
+	account := &types.Account{ID: 1, Phone: "992000000001", Balance: 1000}
+	if err := repo.SaveAccount(account); err != nil {
+		t.Fatalf("SaveAccount(): error = %v", err)
+	}
+
+	accounts, err := repo.LoadAccounts()
+	if err != nil {
+		t.Fatalf("LoadAccounts(): error = %v", err)
+	}
+	if len(accounts) != 1 || accounts[0].Phone != account.Phone || accounts[0].Balance != account.Balance {
+		t.Errorf("LoadAccounts(): got %+v, want [%+v]", accounts, account)
+	}
+}
+
+func TestSQLRepository_Payments_roundtrip(t *testing.T) {
+	repo := newTestSQLRepository(t)
+
+	payment := &types.Payment{ID: "p1", AccountID: 1, Amount: 500, Category: "auto", Status: types.PaymentStatusInProgress}
+	if err := repo.SavePayment(payment); err != nil {
+		t.Fatalf("SavePayment(): error = %v", err)
+	}
+
+	payments, err := repo.LoadPayments()
+	if err != nil {
+		t.Fatalf("LoadPayments(): error = %v", err)
+	}
+	if len(payments) != 1 || payments[0].Status != payment.Status {
+		t.Errorf("LoadPayments(): got %+v, want [%+v]", payments, payment)
+	}
+}
+
+func TestSQLRepository_Favorites_roundtrip(t *testing.T) {
+	repo := newTestSQLRepository(t)
+
+	favorite := &types.Favorite{ID: "f1", AccountID: 1, Name: "mobile", Amount: 300, Category: "auto"}
+	if err := repo.SaveFavorite(favorite); err != nil {
+		t.Fatalf("SaveFavorite(): error = %v", err)
+	}
+
+	favorites, err := repo.LoadFavorites()
+	if err != nil {
+		t.Fatalf("LoadFavorites(): error = %v", err)
+	}
+	if len(favorites) != 1 || favorites[0].Name != favorite.Name {
+		t.Errorf("LoadFavorites(): got %+v, want [%+v]", favorites, favorite)
+	}
+}
+
+func TestSQLRepository_Transactions_roundtrip(t *testing.T) {
+	repo := newTestSQLRepository(t)
+
+	transaction := &types.Transaction{
+		ID:        "t1",
+		Debit:     0,
+		Credit:    1,
+		Amount:    1000,
+		Currency:  types.TJS,
+		Timestamp: time.Now().Truncate(time.Second),
+		Reference: "p1",
+		Note:      "deposit",
+	}
+	if err := repo.SaveTransaction(transaction); err != nil {
+		t.Fatalf("SaveTransaction(): error = %v", err)
+	}
+
+	transactions, err := repo.LoadTransactions()
+	if err != nil {
+		t.Fatalf("LoadTransactions(): error = %v", err)
+	}
+	if len(transactions) != 1 || transactions[0].Credit != transaction.Credit || transactions[0].Amount != transaction.Amount {
+		t.Errorf("LoadTransactions(): got %+v, want [%+v]", transactions, transaction)
+	}
+}